@@ -0,0 +1,191 @@
+package jibberjabber
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Catalog looks up translated message strings for a single language.
+type Catalog interface {
+	// Lookup returns the translation for key, and whether key was found at all.
+	Lookup(tag language.Tag, key string) (string, bool)
+}
+
+// mapCatalog is a Catalog backed by an in-memory key/value map for a single tag.
+type mapCatalog struct {
+	tag      language.Tag
+	messages map[string]string
+}
+
+func (c *mapCatalog) Lookup(tag language.Tag, key string) (string, bool) {
+	if tag != c.tag {
+		return "", false
+	}
+	value, ok := c.messages[key]
+	return value, ok
+}
+
+// LoadJSONCatalog loads a Catalog for tag from a flat JSON object of key/value message
+// strings, read from name within fsys.
+func LoadJSONCatalog(fsys fs.FS, tag language.Tag, name string) (Catalog, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(map[string]string)
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+
+	return &mapCatalog{tag: tag, messages: messages}, nil
+}
+
+// LoadPOCatalog loads a Catalog for tag from a gettext .po file read from name within
+// fsys. It understands plain msgid/msgstr pairs; msgid_plural/plural forms are not
+// supported.
+func LoadPOCatalog(fsys fs.FS, tag language.Tag, name string) (Catalog, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	messages, err := parsePO(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapCatalog{tag: tag, messages: messages}, nil
+}
+
+func parsePO(r io.Reader) (map[string]string, error) {
+	messages := make(map[string]string)
+
+	var msgid, msgstr string
+	var inMsgid, inMsgstr bool
+
+	flush := func() {
+		if msgid != "" {
+			messages[msgid] = msgstr
+		}
+		msgid, msgstr = "", ""
+		inMsgid, inMsgstr = false, false
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		var err error
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			flush()
+		case strings.HasPrefix(line, "msgid "):
+			flush()
+			inMsgid = true
+			msgid, err = unquotePO(strings.TrimPrefix(line, "msgid "))
+		case strings.HasPrefix(line, "msgstr "):
+			inMsgid, inMsgstr = false, true
+			msgstr, err = unquotePO(strings.TrimPrefix(line, "msgstr "))
+		case strings.HasPrefix(line, `"`):
+			var cont string
+			cont, err = unquotePO(line)
+			if inMsgid {
+				msgid += cont
+			} else if inMsgstr {
+				msgstr += cont
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jibberjabber: invalid .po string literal %q: %w", line, err)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	delete(messages, "") // the header entry has an empty msgid
+	return messages, nil
+}
+
+func unquotePO(s string) (string, error) {
+	return strconv.Unquote(s)
+}
+
+// moMagic is the magic number at the start of a little-endian gettext .mo file.
+const moMagic = 0x950412de
+
+// LoadMOCatalog loads a Catalog for tag from a compiled gettext .mo file read from name
+// within fsys.
+func LoadMOCatalog(fsys fs.FS, tag language.Tag, name string) (Catalog, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := parseMO(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapCatalog{tag: tag, messages: messages}, nil
+}
+
+func parseMO(data []byte) (map[string]string, error) {
+	if len(data) < 28 {
+		return nil, fmt.Errorf("jibberjabber: not a valid .mo file")
+	}
+
+	order := binary.ByteOrder(binary.LittleEndian)
+	if order.Uint32(data[0:4]) != moMagic {
+		order = binary.BigEndian
+		if order.Uint32(data[0:4]) != moMagic {
+			return nil, fmt.Errorf("jibberjabber: not a valid .mo file")
+		}
+	}
+
+	numStrings := order.Uint32(data[8:12])
+	origTableOffset := order.Uint32(data[12:16])
+	transTableOffset := order.Uint32(data[16:20])
+
+	readString := func(tableOffset, index uint32) (string, error) {
+		entryOffset := uint64(tableOffset) + uint64(index)*8
+		if entryOffset+8 > uint64(len(data)) {
+			return "", fmt.Errorf("jibberjabber: truncated .mo file")
+		}
+		length := uint64(order.Uint32(data[entryOffset : entryOffset+4]))
+		offset := uint64(order.Uint32(data[entryOffset+4 : entryOffset+8]))
+		if offset+length > uint64(len(data)) {
+			return "", fmt.Errorf("jibberjabber: truncated .mo file")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	messages := make(map[string]string, numStrings)
+	for i := uint32(0); i < numStrings; i++ {
+		key, err := readString(origTableOffset, i)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readString(transTableOffset, i)
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			messages[key] = value
+		}
+	}
+
+	return messages, nil
+}
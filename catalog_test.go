@@ -0,0 +1,174 @@
+package jibberjabber_test
+
+import (
+	"encoding/binary"
+	"testing/fstest"
+
+	. "github.com/cubiest/jibberjabber"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/language"
+)
+
+// buildMO assembles a minimal little-endian gettext .mo file containing messages, a
+// map from msgid to msgstr.
+func buildMO(messages map[string]string) []byte {
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+
+	const headerSize = 28
+	origTableOffset := uint32(headerSize)
+	transTableOffset := origTableOffset + uint32(len(keys))*8
+	stringsOffset := transTableOffset + uint32(len(keys))*8
+
+	var origTable, transTable, blob []byte
+	appendEntry := func(table *[]byte, s string) {
+		entry := make([]byte, 8)
+		binary.LittleEndian.PutUint32(entry[0:4], uint32(len(s)))
+		binary.LittleEndian.PutUint32(entry[4:8], stringsOffset+uint32(len(blob)))
+		*table = append(*table, entry...)
+		blob = append(blob, s...)
+		blob = append(blob, 0)
+	}
+
+	for _, key := range keys {
+		appendEntry(&origTable, key)
+	}
+	for _, key := range keys {
+		appendEntry(&transTable, messages[key])
+	}
+
+	header := make([]byte, headerSize)
+	binary.LittleEndian.PutUint32(header[0:4], 0x950412de)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(keys)))
+	binary.LittleEndian.PutUint32(header[12:16], origTableOffset)
+	binary.LittleEndian.PutUint32(header[16:20], transTableOffset)
+
+	data := append(header, origTable...)
+	data = append(data, transTable...)
+	data = append(data, blob...)
+	return data
+}
+
+var _ = Describe("Catalog loaders", func() {
+	var (
+		fr = language.MustParse("fr")
+		en = language.MustParse("en")
+	)
+
+	Describe("LoadJSONCatalog", func() {
+		It("loads a flat key/value JSON object", func() {
+			fsys := fstest.MapFS{
+				"fr.json": {Data: []byte(`{"greeting": "bonjour"}`)},
+			}
+
+			catalog, err := LoadJSONCatalog(fsys, fr, "fr.json")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			value, found := catalog.Lookup(fr, "greeting")
+			Ω(found).Should(BeTrue())
+			Ω(value).Should(Equal("bonjour"))
+
+			_, found = catalog.Lookup(fr, "missing")
+			Ω(found).Should(BeFalse())
+		})
+
+		It("returns an error for malformed JSON", func() {
+			fsys := fstest.MapFS{"fr.json": {Data: []byte("not json")}}
+			_, err := LoadJSONCatalog(fsys, fr, "fr.json")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("LoadPOCatalog", func() {
+		It("parses msgid/msgstr pairs, including continuation lines", func() {
+			po := "" +
+				"# a comment\n" +
+				"msgid \"\"\n" +
+				"msgstr \"\"\n" +
+				"\n" +
+				"msgid \"greeting\"\n" +
+				"msgstr \"bon\"\n" +
+				"\"jour\"\n"
+
+			fsys := fstest.MapFS{"fr.po": {Data: []byte(po)}}
+			catalog, err := LoadPOCatalog(fsys, fr, "fr.po")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			value, found := catalog.Lookup(fr, "greeting")
+			Ω(found).Should(BeTrue())
+			Ω(value).Should(Equal("bonjour"))
+		})
+
+		It("returns an error for an invalid string literal", func() {
+			fsys := fstest.MapFS{"fr.po": {Data: []byte(`msgid "unterminated`)}}
+			_, err := LoadPOCatalog(fsys, fr, "fr.po")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("LoadMOCatalog", func() {
+		It("parses a compiled .mo file", func() {
+			fsys := fstest.MapFS{
+				"fr.mo": {Data: buildMO(map[string]string{"greeting": "bonjour"})},
+			}
+
+			catalog, err := LoadMOCatalog(fsys, fr, "fr.mo")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			value, found := catalog.Lookup(fr, "greeting")
+			Ω(found).Should(BeTrue())
+			Ω(value).Should(Equal("bonjour"))
+		})
+
+		It("rejects a file that is too short to be a .mo file", func() {
+			fsys := fstest.MapFS{"fr.mo": {Data: []byte("short")}}
+			_, err := LoadMOCatalog(fsys, fr, "fr.mo")
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("rejects a table entry pointing past the end of the file", func() {
+			data := buildMO(map[string]string{"greeting": "bonjour"})
+			data = data[:len(data)-len("bonjour")-1] // truncate the translation blob away entirely
+			fsys := fstest.MapFS{"fr.mo": {Data: data}}
+
+			_, err := LoadMOCatalog(fsys, fr, "fr.mo")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Server.LoadCatalogs and Server.Translate", func() {
+		It("loads one catalog per supported language and translates by tag", func() {
+			fsys := fstest.MapFS{
+				"fr.json": {Data: []byte(`{"greeting": "bonjour"}`)},
+				"en.json": {Data: []byte(`{"greeting": "hello", "farewell": "bye"}`)},
+			}
+
+			server := NewServer()
+			server.SetSupportedLanguages(map[language.Tag]string{
+				fr: "fr.json",
+				en: "en.json",
+			})
+			server.SetFallbackLanguage(en)
+
+			Ω(server.LoadCatalogs(fsys)).Should(Succeed())
+
+			Ω(server.Translate(fr, "greeting")).Should(Equal("bonjour"))
+			// "farewell" is missing from the fr catalog, so it falls back to en's.
+			Ω(server.Translate(fr, "farewell")).Should(Equal("bye"))
+			// a key missing from every catalog yields the empty string.
+			Ω(server.Translate(fr, "missing")).Should(Equal(""))
+		})
+
+		It("reports which catalog failed to load", func() {
+			fsys := fstest.MapFS{}
+			server := NewServer()
+			server.SetSupportedLanguages(map[language.Tag]string{fr: "fr.json"})
+
+			Ω(server.LoadCatalogs(fsys)).Should(MatchError(ContainSubstring(`"fr.json"`)))
+		})
+	})
+})
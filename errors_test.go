@@ -0,0 +1,38 @@
+package jibberjabber_test
+
+import (
+	"errors"
+
+	. "github.com/cubiest/jibberjabber"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/language"
+)
+
+var _ = Describe("jibberjabber errors", func() {
+	var server *Server
+
+	BeforeEach(func() {
+		server = NewServer()
+	})
+
+	It("satisfies errors.Is against its sentinel", func() {
+		_, err := server.StringToLanguageTag("not a valid @@ tag")
+		Ω(errors.Is(err, ErrLangParse)).Should(BeTrue())
+		Ω(errors.Is(err, ErrLangUnsupported)).Should(BeFalse())
+	})
+
+	It("exposes the underlying cause via errors.As", func() {
+		_, err := server.StringToLanguageTag("xx")
+
+		var valueErr language.ValueError
+		Ω(errors.As(err, &valueErr)).Should(BeTrue())
+		Ω(valueErr.Subtag()).Should(Equal("xx"))
+	})
+
+	It("still recognizes the sentinel through IsError, deprecated but functional", func() {
+		_, err := server.StringToLanguageTag("not a valid @@ tag")
+		Ω(IsError(err, ErrLangParse)).Should(BeTrue())
+	})
+})
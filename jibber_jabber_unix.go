@@ -0,0 +1,124 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package jibberjabber
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DetectLanguageTags returns the user's language preferences in priority order, as
+// reported by the operating system.
+//
+// It honours the GNU gettext priority list in LANGUAGE (colon-separated) ahead of the
+// single locale found in LC_MESSAGES, LC_ALL, or LANG, in that order -- the same
+// precedence gettext itself uses. Tags that cannot be parsed are skipped rather than
+// failing the whole call.
+// Returns ErrLangDetectFail, if no language tags could be detected or parsed.
+func DetectLanguageTags() ([]language.Tag, error) {
+	var locales []string
+
+	if languageEnv := os.Getenv("LANGUAGE"); languageEnv != "" {
+		for _, locale := range strings.Split(languageEnv, ":") {
+			if locale != "" {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	for _, envVar := range []string{"LC_MESSAGES", "LC_ALL", "LANG"} {
+		if locale := os.Getenv(envVar); locale != "" {
+			locales = append(locales, locale)
+			break
+		}
+	}
+
+	if len(locales) == 0 {
+		return nil, ErrLangDetectFail
+	}
+
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		base, territory := splitLocale(locale)
+		bcp := base
+		if territory != "" {
+			bcp += "-" + territory
+		}
+
+		tag, err := language.Parse(bcp)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		return nil, ErrLangDetectFail
+	}
+
+	return tags, nil
+}
+
+// DetectIETF returns the user's primary locale as an IETF language tag (e.g. "fr-FR"),
+// read from LC_MESSAGES, LC_ALL, or LANG, in that order -- the same precedence gettext
+// itself uses.
+// Returns ErrLangDetectFail, if no locale is set in any of those variables.
+func DetectIETF() (string, error) {
+	for _, envVar := range []string{"LC_MESSAGES", "LC_ALL", "LANG"} {
+		if locale := os.Getenv(envVar); locale != "" {
+			base, territory := splitLocale(locale)
+			ietf := base
+			if territory != "" {
+				ietf += "-" + territory
+			}
+			return ietf, nil
+		}
+	}
+
+	return "", ErrLangDetectFail
+}
+
+// DetectLanguage returns the language portion (e.g. "fr") of DetectIETF's result.
+func DetectLanguage() (string, error) {
+	ietf, err := DetectIETF()
+	if err != nil {
+		return "", err
+	}
+
+	lang, _ := splitLocale(ietf)
+	return lang, nil
+}
+
+// DetectTerritory returns the territory portion (e.g. "FR") of DetectIETF's result.
+// Returns ErrLangDetectFail, if the detected locale has no territory.
+func DetectTerritory() (string, error) {
+	ietf, err := DetectIETF()
+	if err != nil {
+		return "", err
+	}
+
+	_, territory := splitLocale(ietf)
+	if territory == "" {
+		return "", ErrLangDetectFail
+	}
+
+	return territory, nil
+}
+
+// DetectLanguageTag returns the user's primary locale as a single parsed language.Tag.
+// Returns ErrLangDetectFail, if no locale is set, or it cannot be parsed.
+func DetectLanguageTag() (language.Tag, error) {
+	ietf, err := DetectIETF()
+	if err != nil {
+		return language.Und, err
+	}
+
+	tag, err := language.Parse(ietf)
+	if err != nil {
+		return language.Und, ErrLangDetectFail
+	}
+
+	return tag, nil
+}
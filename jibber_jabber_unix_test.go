@@ -156,4 +156,53 @@ var _ = Describe("Unix", func() {
 		})
 	})
 
+	Describe("#DetectLanguageTags", func() {
+		Context("Returns the ordered language preference list", func() {
+			It("should prefer LANGUAGE's colon-separated priority list over LC_MESSAGES/LC_ALL/LANG", func() {
+				os.Setenv("LANGUAGE", "de_AT:de:en")
+				defer os.Setenv("LANGUAGE", "")
+				os.Setenv("LANG", "fr_FR.UTF-8")
+
+				tags, err := DetectLanguageTags()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(len(tags)).Should(BeNumerically(">=", 3))
+
+				base, _ := tags[0].Base()
+				region, _ := tags[0].Region()
+				Ω(base.String()).Should(Equal("de"))
+				Ω(region.String()).Should(Equal("AT"))
+			})
+
+			It("should fall back to LC_MESSAGES/LC_ALL/LANG if LANGUAGE isn't set", func() {
+				os.Setenv("LC_MESSAGES", "fr_FR.UTF-8")
+
+				tags, err := DetectLanguageTags()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(tags).Should(HaveLen(1))
+
+				base, _ := tags[0].Base()
+				Ω(base.String()).Should(Equal("fr"))
+			})
+
+			It("should skip tags it cannot parse rather than failing the whole call", func() {
+				os.Setenv("LANGUAGE", "!!!not-a-locale!!!:fr_FR.UTF-8")
+				defer os.Setenv("LANGUAGE", "")
+				os.Setenv("LANG", "")
+
+				tags, err := DetectLanguageTags()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(tags).Should(HaveLen(1))
+
+				base, _ := tags[0].Base()
+				Ω(base.String()).Should(Equal("fr"))
+			})
+
+			It("should return an error if no language tags could be detected", func() {
+				os.Setenv("LANG", "")
+				_, err := DetectLanguageTags()
+				Ω(err.Error()).Should(Equal(COULD_NOT_DETECT_PACKAGE_ERROR_MESSAGE))
+			})
+		})
+	})
+
 })
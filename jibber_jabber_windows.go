@@ -0,0 +1,147 @@
+// +build windows
+
+package jibberjabber
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/text/language"
+)
+
+const muiLanguageNameFlag = 0x8 // MUI_LANGUAGE_NAME: return BCP 47 tags instead of LCIDs
+
+var (
+	kernel32                          = syscall.NewLazyDLL("kernel32.dll")
+	procGetUserPreferredUILanguages   = kernel32.NewProc("GetUserPreferredUILanguages")
+	procGetSystemPreferredUILanguages = kernel32.NewProc("GetSystemPreferredUILanguages")
+)
+
+// DetectLanguageTags returns the user's language preferences in priority order, as
+// reported by Windows. It prefers the per-user list (GetUserPreferredUILanguages) and
+// falls back to the system-wide list (GetSystemPreferredUILanguages) if the user has
+// not configured one of their own.
+// Returns ErrLangDetectFail, if no language tags could be detected or parsed.
+func DetectLanguageTags() ([]language.Tag, error) {
+	locales, err := getPreferredUILanguages(procGetUserPreferredUILanguages)
+	if err != nil || len(locales) == 0 {
+		locales, err = getPreferredUILanguages(procGetSystemPreferredUILanguages)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		tag, parseErr := language.Parse(locale)
+		if parseErr != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		return nil, ErrLangDetectFail
+	}
+
+	return tags, nil
+}
+
+// DetectLanguageTag returns the user's primary language preference, the first tag from
+// DetectLanguageTags.
+// Returns ErrLangDetectFail, if no language tags could be detected or parsed.
+func DetectLanguageTag() (language.Tag, error) {
+	tags, err := DetectLanguageTags()
+	if err != nil {
+		return language.Und, err
+	}
+
+	return tags[0], nil
+}
+
+// DetectIETF returns the user's primary language preference as an IETF BCP 47 string.
+// Returns ErrLangDetectFail, if no language tags could be detected or parsed.
+func DetectIETF() (string, error) {
+	tag, err := DetectLanguageTag()
+	if err != nil {
+		return "", err
+	}
+
+	return tag.String(), nil
+}
+
+// DetectLanguage returns the language portion (e.g. "fr") of DetectIETF's result.
+func DetectLanguage() (string, error) {
+	tag, err := DetectLanguageTag()
+	if err != nil {
+		return "", err
+	}
+
+	base, _ := tag.Base()
+	return base.String(), nil
+}
+
+// DetectTerritory returns the territory portion (e.g. "FR") of DetectIETF's result.
+// Returns ErrLangDetectFail, if the detected locale has no territory.
+func DetectTerritory() (string, error) {
+	tag, err := DetectLanguageTag()
+	if err != nil {
+		return "", err
+	}
+
+	region, confidence := tag.Region()
+	if confidence != language.Exact {
+		return "", ErrLangDetectFail
+	}
+
+	return region.String(), nil
+}
+
+// getPreferredUILanguages calls one of the GetUserPreferredUILanguages /
+// GetSystemPreferredUILanguages procs, which share an identical two-call
+// (size-then-fill) calling convention, and returns the BCP 47 tags it reports.
+func getPreferredUILanguages(proc *syscall.LazyProc) ([]string, error) {
+	var numLanguages uint32
+	var bufferSize uint32
+
+	ret, _, _ := proc.Call(
+		uintptr(muiLanguageNameFlag),
+		uintptr(unsafe.Pointer(&numLanguages)),
+		0,
+		uintptr(unsafe.Pointer(&bufferSize)),
+	)
+	if ret == 0 || bufferSize == 0 {
+		return nil, ErrLangDetectFail
+	}
+
+	buffer := make([]uint16, bufferSize)
+	ret, _, _ = proc.Call(
+		uintptr(muiLanguageNameFlag),
+		uintptr(unsafe.Pointer(&numLanguages)),
+		uintptr(unsafe.Pointer(&buffer[0])),
+		uintptr(unsafe.Pointer(&bufferSize)),
+	)
+	if ret == 0 {
+		return nil, ErrLangDetectFail
+	}
+
+	return splitMUIStringBuffer(buffer), nil
+}
+
+// splitMUIStringBuffer splits the NUL-delimited, double-NUL-terminated buffer returned
+// by the Windows MUI language APIs into individual BCP 47 tags.
+func splitMUIStringBuffer(buffer []uint16) []string {
+	var locales []string
+
+	start := 0
+	for i, c := range buffer {
+		if c == 0 {
+			if i > start {
+				locales = append(locales, syscall.UTF16ToString(buffer[start:i]))
+			}
+			start = i + 1
+		}
+	}
+
+	return locales
+}
@@ -1,8 +1,11 @@
 package jibberjabber
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -11,14 +14,46 @@ import (
 	"golang.org/x/text/language/display"
 )
 
+// COULD_NOT_DETECT_PACKAGE_ERROR_MESSAGE is the error message behind ErrLangDetectFail,
+// exported for callers (and tests) written against the original jibber_jabber API.
+const COULD_NOT_DETECT_PACKAGE_ERROR_MESSAGE = "could not detect Language"
+
 var (
-	ErrLangDetectFail          = errors.New("could not detect Language")
+	ErrLangDetectFail          = errors.New(COULD_NOT_DETECT_PACKAGE_ERROR_MESSAGE)
 	ErrLangFallbackUndefined   = errors.New("no fallback language defined")
 	ErrLangFallbackUnsupported = errors.New("defined fallback language is not supported")
 	ErrLangUnsupported         = errors.New("language not supported")
 	ErrLangParse               = errors.New("language identifier cannot be parsed")
 )
 
+// jjError pairs one of jibberjabber's sentinel errors (e.g. ErrLangParse) with the
+// underlying cause that triggered it (e.g. the error returned by language.Parse), so
+// that errors.Is(err, ErrLangParse) and errors.As against the cause both work. This
+// replaces the old fmt.Errorf("%v: %w", sentinel.Error(), cause) pattern, whose sentinel
+// half was just formatted text and so never satisfied errors.Is.
+type jjError struct {
+	sentinel error
+	cause    error
+}
+
+func (e *jjError) Error() string {
+	return fmt.Sprintf("%v: %v", e.sentinel, e.cause)
+}
+
+func (e *jjError) Is(target error) bool {
+	return e.sentinel == target
+}
+
+func (e *jjError) Unwrap() error {
+	return e.cause
+}
+
+// wrapErr returns sentinel wrapped around cause so that both errors.Is(err, sentinel)
+// and errors.As(err, &cause) succeed.
+func wrapErr(sentinel, cause error) error {
+	return &jjError{sentinel: sentinel, cause: cause}
+}
+
 func splitLocale(locale string) (string, string) {
 	formattedLocale := strings.Split(locale, ".")[0]
 	formattedLocale = strings.Replace(formattedLocale, "-", "_", -1)
@@ -33,81 +68,272 @@ func splitLocale(locale string) (string, string) {
 }
 
 /**
- * languageServer
+ * Server
  */
 
-type languageServer struct {
+type Server struct {
+	mu sync.Mutex // guards everything below; each Server has its own, so independent Servers never serialize on one another
+
 	supportedLanguages map[language.Tag]string // the string can be used to link to a localization file for that language
 	fallbackLanguage   language.Tag
+
+	matcher     language.Matcher // built lazily from supportedLanguages, invalidated by SetSupportedLanguages
+	matcherTags []language.Tag   // indices returned by matcher.Match() index into this slice
+
+	catalogs map[language.Tag]Catalog // loaded by LoadCatalogs, used by Translate
 }
 
 var (
 	languageServerSingletonOnce sync.Once
-	languageServerInstance      *languageServer
-	languageServerMutex         = &sync.Mutex{}
+	languageServerInstance      *Server
 )
 
-func LanguageServer() *languageServer {
+// LanguageServer returns the default, process-wide Server instance. It exists for
+// backwards compatibility with code written against jibberjabber's original
+// singleton-only API; new code that needs more than one independently configured
+// Server (e.g. one per tenant, or one negotiated per HTTP request) should call
+// NewServer instead.
+func LanguageServer() *Server {
 	languageServerSingletonOnce.Do(func() {
 		if languageServerInstance == nil {
-			languageServerInstance = new(languageServer)
+			languageServerInstance = new(Server)
 		}
 	})
 	return languageServerInstance
 }
 
+// NewServer creates a new, independently configured Server. Unlike LanguageServer,
+// which always returns the same process-wide instance, each Server returned by
+// NewServer has its own supportedLanguages and fallbackLanguage, so a single process
+// can serve more than one of each concurrently.
+func NewServer() *Server {
+	return new(Server)
+}
+
+type serverContextKey struct{}
+
+// WithServer returns a copy of ctx carrying server, for later retrieval with
+// FromContext. This lets middleware attach a per-request configured Server -- for
+// example, one whose supportedLanguages were negotiated from the request's
+// Accept-Language header -- for downstream handlers to pick up.
+func WithServer(ctx context.Context, server *Server) context.Context {
+	return context.WithValue(ctx, serverContextKey{}, server)
+}
+
+// FromContext returns the Server attached to ctx via WithServer. If none was attached,
+// it returns the default LanguageServer() instance.
+func FromContext(ctx context.Context) *Server {
+	if server, ok := ctx.Value(serverContextKey{}).(*Server); ok {
+		return server
+	}
+	return LanguageServer()
+}
+
 // SetSupportedLanguages defines the supported languages checked against in other funcs.
 // The values (type `string`) can be used to link to a localization file for that language.
-func (server *languageServer) SetSupportedLanguages(supported map[language.Tag]string) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) SetSupportedLanguages(supported map[language.Tag]string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	server.supportedLanguages = supported
+	server.matcher = nil
+	server.matcherTags = nil
 }
 
 // GetSupportedLanguages returns the supported languages.
-func (server *languageServer) GetSupportedLanguages() map[language.Tag]string {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) GetSupportedLanguages() map[language.Tag]string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	return server.supportedLanguages
 }
 
 // SetFallbackLanguage defines the language used as a fallback language Tag if any other func returns no valid value.
-func (server *languageServer) SetFallbackLanguage(fallback language.Tag) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) SetFallbackLanguage(fallback language.Tag) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	server.fallbackLanguage = fallback
 }
 
 // GetFallbackLanguage returns the language fallback.
-func (server *languageServer) GetFallbackLanguage() language.Tag {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) GetFallbackLanguage() language.Tag {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	return server.fallbackLanguage
 }
 
+// LoadCatalogs loads a Catalog for every entry in supportedLanguages, treating the
+// entry's value as a path within fsys (so callers can pass an embed.FS). The loader is
+// chosen by file extension: ".json" for LoadJSONCatalog, ".po" for LoadPOCatalog, ".mo"
+// for LoadMOCatalog. Call this after SetSupportedLanguages; it replaces any catalogs
+// loaded by a previous call.
+func (server *Server) LoadCatalogs(fsys fs.FS) error {
+	server.mu.Lock()
+	supported := server.supportedLanguages
+	server.mu.Unlock()
+
+	catalogs := make(map[language.Tag]Catalog, len(supported))
+
+	for tag, name := range supported {
+		catalog, err := loadCatalog(fsys, tag, name)
+		if err != nil {
+			return fmt.Errorf("jibberjabber: loading catalog for %v from %q: %w", tag, name, err)
+		}
+		catalogs[tag] = catalog
+	}
+
+	server.mu.Lock()
+	server.catalogs = catalogs
+	server.mu.Unlock()
+
+	return nil
+}
+
+func loadCatalog(fsys fs.FS, tag language.Tag, name string) (Catalog, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return LoadJSONCatalog(fsys, tag, name)
+	case ".po":
+		return LoadPOCatalog(fsys, tag, name)
+	case ".mo":
+		return LoadMOCatalog(fsys, tag, name)
+	default:
+		return nil, fmt.Errorf("jibberjabber: unrecognized catalog file extension %q", path.Ext(name))
+	}
+}
+
+// Translate returns the message for key in tag's catalog, as loaded by LoadCatalogs. If
+// key is missing from tag's catalog, it falls back to fallbackLanguage's catalog -- the
+// same fallback discipline GetSupportedLanguageValue and friends already apply to
+// localization-file paths, but applied here to message strings. If no catalog has a
+// translation for key, Translate returns the empty string.
+func (server *Server) Translate(tag language.Tag, key string) string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if catalog, ok := server.catalogs[tag]; ok {
+		if value, found := catalog.Lookup(tag, key); found {
+			return value
+		}
+	}
+
+	if catalog, ok := server.catalogs[server.fallbackLanguage]; ok {
+		if value, found := catalog.Lookup(server.fallbackLanguage, key); found {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// buildMatcherLocked (re)builds the language.Matcher used by NegotiateLanguage from the
+// current supportedLanguages. tags is sorted before being handed to language.NewMatcher
+// so the resulting matcher -- and in particular its "best guess" tag for a preferred
+// list that matches nothing well -- doesn't depend on Go's randomized map iteration
+// order. Callers must hold server.mu.
+func (server *Server) buildMatcherLocked() {
+	tags := make([]language.Tag, 0, len(server.supportedLanguages))
+	for tag := range server.supportedLanguages {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].String() < tags[j].String()
+	})
+
+	server.matcherTags = tags
+	server.matcher = language.NewMatcher(tags)
+}
+
+// NegotiateLanguage picks the best supported language for preferred, an ordered list of
+// language tags (most preferred first), using golang.org/x/text/language's BCP 47
+// matching rules. Unlike a plain lookup in supportedLanguages, this also matches on
+// script and region fallbacks, e.g. zh-HK against zh-Hant, or pt-BR against pt.
+// The returned confidence indicates how good the match is; see language.Confidence.
+// Returns ErrLangUnsupported if no languages have been registered via SetSupportedLanguages.
+func (server *Server) NegotiateLanguage(preferred ...language.Tag) (language.Tag, language.Confidence, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.supportedLanguages) == 0 {
+		return language.Und, language.No, ErrLangUnsupported
+	}
+
+	if server.matcher == nil {
+		server.buildMatcherLocked()
+	}
+
+	_, index, confidence := server.matcher.Match(preferred...)
+
+	return server.matcherTags[index], confidence, nil
+}
+
+// NegotiateLanguageFromAcceptHeader is NegotiateLanguage for callers that have a raw
+// HTTP Accept-Language header value rather than an already-parsed preference list.
+// Returns ErrLangParse if header cannot be parsed.
+func (server *Server) NegotiateLanguageFromAcceptHeader(header string) (language.Tag, language.Confidence, error) {
+	preferred, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return language.Und, language.No, wrapErr(ErrLangParse, err)
+	}
+
+	return server.NegotiateLanguage(preferred...)
+}
+
 // DetectSupportedLanguage returns the language tag detected from the system.
 // If it's not supported, it returns the fallback.
 // Returns ErrLangParse, if library cannot detect language or parse value given from your operating system.
 // Returns ErrLangFallbackUndefined, if fallback is undefined.
 // Returns ErrLangFallbackUnsupported, if fallaback is defined but unsupported.
 // If you want to check for jibberjabber errors, call `jibberjabber.IsError()`.
-func (server *languageServer) DetectSupportedLanguage() (language.Tag, error) {
+func (server *Server) DetectSupportedLanguage() (language.Tag, error) {
 
 	tag, err := DetectLanguageTag()
 	if err != nil {
-		return language.Und, fmt.Errorf("%v: %w", ErrLangParse.Error(), err)
+		return language.Und, wrapErr(ErrLangParse, err)
 	}
 
 	if server.LanguageTagIsSupported(tag) {
 		return tag, nil
 	}
 
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	fallbackTag := server.fallbackLanguage
+	if fallbackTag == language.Und {
+		return language.Und, ErrLangFallbackUndefined
+	} else if _, supported := server.supportedLanguages[fallbackTag]; !supported {
+		return language.Und, ErrLangFallbackUnsupported
+	} else {
+		return fallbackTag, nil
+	}
+}
+
+// DetectSupportedLanguages returns the best supported language tag detected from the
+// operating system's full, ordered language preference list (see DetectLanguageTags),
+// matched via the same BCP 47 rules as NegotiateLanguage. Unlike DetectSupportedLanguage,
+// which only inspects the system's single primary locale, this considers the whole
+// priority list -- e.g. preferring de-AT, then de, then en, rather than falling
+// straight through to the fallback the moment the first tag isn't an exact match.
+// Returns ErrLangParse, if library cannot detect or parse any language tags from your operating system.
+// Returns ErrLangFallbackUndefined, if fallback is undefined.
+// Returns ErrLangFallbackUnsupported, if fallaback is defined but unsupported.
+// If you want to check for jibberjabber errors, call `errors.Is()`.
+func (server *Server) DetectSupportedLanguages() (language.Tag, error) {
+	tags, err := DetectLanguageTags()
+	if err != nil {
+		return language.Und, wrapErr(ErrLangParse, err)
+	}
+
+	tag, confidence, err := server.NegotiateLanguage(tags...)
+	if err == nil && confidence > language.No {
+		return tag, nil
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	fallbackTag := server.fallbackLanguage
 	if fallbackTag == language.Und {
@@ -120,9 +346,9 @@ func (server *languageServer) DetectSupportedLanguage() (language.Tag, error) {
 }
 
 // ListSupportedLanguages returns the language tags in a language.Tag slice.
-func (server *languageServer) ListSupportedLanguages() []language.Tag {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) ListSupportedLanguages() []language.Tag {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	supportedLangTags := make([]language.Tag, 0, len(server.supportedLanguages))
 
@@ -134,9 +360,9 @@ func (server *languageServer) ListSupportedLanguages() []language.Tag {
 }
 
 // ListSupportedLanguagesAsStrings returns the language tags in a slice of string representation of the language tags.
-func (server *languageServer) ListSupportedLanguagesAsStrings() []string {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) ListSupportedLanguagesAsStrings() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	supportedLangs := make([]string, 0, len(server.supportedLanguages))
 
@@ -148,16 +374,16 @@ func (server *languageServer) ListSupportedLanguagesAsStrings() []string {
 }
 
 // ListSupportedLanguagesAsStringsSorted returns the language tags in a slice of string representation of the language tags, alphabetically sorted.
-func (server *languageServer) ListSupportedLanguagesAsStringsSorted() []string {
+func (server *Server) ListSupportedLanguagesAsStringsSorted() []string {
 	supportedLangs := server.ListSupportedLanguagesAsStrings()
 	sort.Strings(supportedLangs)
 	return supportedLangs
 }
 
 // ListSupportedLanguagesForDisplay returns the language tags in a slice of human readable strings.
-func (server *languageServer) ListSupportedLanguagesForDisplay() []string {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) ListSupportedLanguagesForDisplay() []string {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	supportedLangs := make([]string, 0, len(server.supportedLanguages))
 
@@ -169,7 +395,7 @@ func (server *languageServer) ListSupportedLanguagesForDisplay() []string {
 }
 
 // ListSupportedLanguagesForDisplaySorted returns the language tags in a string slice, alphabetically sorted.
-func (server *languageServer) ListSupportedLanguagesForDisplaySorted() []string {
+func (server *Server) ListSupportedLanguagesForDisplaySorted() []string {
 	supportedLangs := server.ListSupportedLanguagesForDisplay()
 	sort.Strings(supportedLangs)
 	return supportedLangs
@@ -177,9 +403,9 @@ func (server *languageServer) ListSupportedLanguagesForDisplaySorted() []string
 
 // ListSupportedLanguagesSorted returns the language tags + their strings sorted alphabetically by string.
 // Use the elements for the first return value as key for the second return value.
-func (server *languageServer) ListSupportedLanguagesSorted() ([]string, map[string]language.Tag) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) ListSupportedLanguagesSorted() ([]string, map[string]language.Tag) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	supportedLangs := make([]string, 0, len(server.supportedLanguages))
 	supportedLangTags := make(map[string]language.Tag)
@@ -198,13 +424,13 @@ func (server *languageServer) ListSupportedLanguagesSorted() ([]string, map[stri
 // LanguageIsSupported returns true if the given BCP 47 string is in the list of supported languages.
 // Returns ErrLangParse, if any parsing issue occured.
 // If you want to check for jibberjabber errors, call `jibberjabber.IsError()`.
-func (server *languageServer) LanguageIsSupported(bcp string) (bool, error) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) LanguageIsSupported(bcp string) (bool, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	lang, parseErr := language.Parse(bcp)
 	if parseErr != nil {
-		return false, fmt.Errorf("%v: %w", ErrLangParse.Error(), parseErr)
+		return false, wrapErr(ErrLangParse, parseErr)
 	}
 
 	_, supported := server.supportedLanguages[lang]
@@ -213,9 +439,9 @@ func (server *languageServer) LanguageIsSupported(bcp string) (bool, error) {
 }
 
 // LanguageTagIsSupported returns true if the given language tag is in the list of supported languages.
-func (server *languageServer) LanguageTagIsSupported(lang language.Tag) bool {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) LanguageTagIsSupported(lang language.Tag) bool {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	_, supported := server.supportedLanguages[lang]
 
@@ -225,13 +451,13 @@ func (server *languageServer) LanguageTagIsSupported(lang language.Tag) bool {
 // StringToLanguageTag returns language tag for given BCP 47 string.
 // Returns ErrLangParse, if parsing fails.
 // If you want to check for jibberjabber errors, call `jibberjabber.IsError()`.
-func (server *languageServer) StringToLanguageTag(bcp string) (language.Tag, error) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) StringToLanguageTag(bcp string) (language.Tag, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	lang, parseErr := language.Parse(bcp)
 	if parseErr != nil {
-		return language.Und, fmt.Errorf("%v: %w", ErrLangParse.Error(), parseErr)
+		return language.Und, wrapErr(ErrLangParse, parseErr)
 	}
 	return lang, nil
 }
@@ -241,9 +467,9 @@ func (server *languageServer) StringToLanguageTag(bcp string) (language.Tag, err
 // Returns ErrLangUnsupported, if language could be parsed, but is not supported.
 // Returns ErrLangFallbackUndefined, if ErrLangUnsupported and fallback is undefined.
 // Returns ErrLangFallbackUnsupported, if ErrLangUnsupported and fallaback is defined but unsupported.
-func (server *languageServer) StringToSupportedLanguageTag(bcp string) (language.Tag, error) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) StringToSupportedLanguageTag(bcp string) (language.Tag, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	var err error
 
@@ -274,15 +500,15 @@ func (server *languageServer) StringToSupportedLanguageTag(bcp string) (language
 // Returns ErrLangUnsupported, if language could be parsed, but is not supported.
 // Returns ErrLangFallbackUndefined, if ErrLangUnsupported and fallback is undefined.
 // Returns ErrLangFallbackUnsupported, if ErrLangUnsupported and fallaback is defined but unsupported.
-func (server *languageServer) GetSupportedLanguageValue(bcp string) (string, error) {
+func (server *Server) GetSupportedLanguageValue(bcp string) (string, error) {
 
 	tag, err := server.StringToSupportedLanguageTag(bcp)
 	if errors.Is(err, ErrLangFallbackUndefined) || errors.Is(err, ErrLangFallbackUnsupported) {
 		return "", err
 	}
 
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	return server.supportedLanguages[tag], err
 }
@@ -292,9 +518,9 @@ func (server *languageServer) GetSupportedLanguageValue(bcp string) (string, err
 // Returns ErrLangUnsupported, if language could be parsed, but is not supported.
 // Returns ErrLangFallbackUndefined, if ErrLangUnsupported and fallback is undefined.
 // Returns ErrLangFallbackUnsupported, if ErrLangUnsupported and fallaback is defined but unsupported.
-func (server *languageServer) GetSupportedLanguageValueByTag(lang language.Tag) (string, error) {
-	languageServerMutex.Lock()
-	defer languageServerMutex.Unlock()
+func (server *Server) GetSupportedLanguageValueByTag(lang language.Tag) (string, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
 
 	var err error
 
@@ -315,7 +541,10 @@ func (server *languageServer) GetSupportedLanguageValueByTag(lang language.Tag)
 }
 
 // IsError checks an error you received from one of jibberjabber's funcs for a jibberjabber error like `ErrLangDetectFail`.
-// Reason you cannot use e.g. `errors.Is()`: currently, golang does not allow native chain-wrapping errors. Therefore, `errors.Unwrap()`, `errors.Is()` & Co. won't return `true` for jibberjabber errors.
-func IsError(err error, jjError error) bool {
-	return strings.HasPrefix(err.Error(), jjError.Error())
+//
+// Deprecated: use errors.Is(err, target) instead. jibberjabber errors now wrap their
+// sentinel with Is/Unwrap support rather than string-formatting it, so errors.Is works
+// natively; IsError is kept only for existing callers.
+func IsError(err error, target error) bool {
+	return errors.Is(err, target)
 }
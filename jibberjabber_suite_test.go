@@ -0,0 +1,13 @@
+package jibberjabber_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestJibberjabber(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jibberjabber Suite")
+}
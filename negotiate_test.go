@@ -0,0 +1,93 @@
+package jibberjabber_test
+
+import (
+	"errors"
+
+	. "github.com/cubiest/jibberjabber"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/language"
+)
+
+var _ = Describe("Server.NegotiateLanguage", func() {
+	var (
+		server *Server
+		zhHant = language.MustParse("zh-Hant")
+		pt     = language.MustParse("pt")
+		en     = language.MustParse("en")
+	)
+
+	BeforeEach(func() {
+		server = NewServer()
+		server.SetSupportedLanguages(map[language.Tag]string{
+			zhHant: "zh-hant.json",
+			pt:     "pt.json",
+			en:     "en.json",
+		})
+	})
+
+	It("matches an exact supported tag", func() {
+		tag, _, err := server.NegotiateLanguage(en)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(tag).Should(Equal(en))
+	})
+
+	It("falls back from a region to the matching script, e.g. zh-HK to zh-Hant", func() {
+		tag, _, err := server.NegotiateLanguage(language.MustParse("zh-HK"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(tag).Should(Equal(zhHant))
+	})
+
+	It("falls back from a region to the base language, e.g. pt-BR to pt", func() {
+		tag, _, err := server.NegotiateLanguage(language.MustParse("pt-BR"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(tag).Should(Equal(pt))
+	})
+
+	It("returns ErrLangUnsupported when nothing has been registered", func() {
+		empty := NewServer()
+		_, _, err := empty.NegotiateLanguage(en)
+		Ω(errors.Is(err, ErrLangUnsupported)).Should(BeTrue())
+	})
+
+	Describe("NegotiateLanguageFromAcceptHeader", func() {
+		It("picks the best match from a ranked Accept-Language header", func() {
+			tag, _, err := server.NegotiateLanguageFromAcceptHeader("zh-HK;q=0.9, pt-BR;q=0.5")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(tag).Should(Equal(zhHant))
+		})
+
+		It("returns ErrLangParse for a malformed header", func() {
+			_, _, err := server.NegotiateLanguageFromAcceptHeader(";;;not a header;;;")
+			Ω(errors.Is(err, ErrLangParse)).Should(BeTrue())
+		})
+	})
+
+	It("picks the same no-confidence-match default every time, independent of map iteration order", func() {
+		// preferred matches none of the supported languages at all, so the matcher
+		// falls back to its "best guess" -- which must be reproducible rather than
+		// depending on the random order supportedLanguages happens to be ranged over.
+		preferred := language.MustParse("ar")
+
+		var first language.Tag
+		for i := 0; i < 50; i++ {
+			fresh := NewServer()
+			fresh.SetSupportedLanguages(map[language.Tag]string{
+				zhHant: "zh-hant.json",
+				pt:     "pt.json",
+				en:     "en.json",
+			})
+
+			tag, confidence, err := fresh.NegotiateLanguage(preferred)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(confidence).Should(Equal(language.No))
+
+			if i == 0 {
+				first = tag
+			} else {
+				Ω(tag).Should(Equal(first))
+			}
+		}
+	})
+})
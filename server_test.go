@@ -0,0 +1,45 @@
+package jibberjabber_test
+
+import (
+	"context"
+
+	. "github.com/cubiest/jibberjabber"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/language"
+)
+
+var _ = Describe("Per-request Server scoping", func() {
+	It("lets two NewServer instances carry independent supported languages", func() {
+		fr := language.MustParse("fr")
+		en := language.MustParse("en")
+
+		tenantA := NewServer()
+		tenantA.SetSupportedLanguages(map[language.Tag]string{fr: "fr.json"})
+
+		tenantB := NewServer()
+		tenantB.SetSupportedLanguages(map[language.Tag]string{en: "en.json"})
+
+		Ω(tenantA.LanguageTagIsSupported(fr)).Should(BeTrue())
+		Ω(tenantA.LanguageTagIsSupported(en)).Should(BeFalse())
+		Ω(tenantB.LanguageTagIsSupported(en)).Should(BeTrue())
+		Ω(tenantB.LanguageTagIsSupported(fr)).Should(BeFalse())
+	})
+
+	Describe("WithServer/FromContext", func() {
+		It("returns the Server attached to the context", func() {
+			fr := language.MustParse("fr")
+			server := NewServer()
+			server.SetSupportedLanguages(map[language.Tag]string{fr: "fr.json"})
+
+			ctx := WithServer(context.Background(), server)
+
+			Ω(FromContext(ctx)).Should(BeIdenticalTo(server))
+		})
+
+		It("falls back to the default LanguageServer() when nothing was attached", func() {
+			Ω(FromContext(context.Background())).Should(BeIdenticalTo(LanguageServer()))
+		})
+	})
+})